@@ -0,0 +1,212 @@
+// Package addons computes and executes reconciliation plans for the set of
+// EKS add-ons attached to a cluster.
+package addons
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eks"
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+// maxConcurrentAddons bounds the number of add-ons reconciled in parallel so
+// a cluster with a long add-on list doesn't overwhelm the EKS API.
+const maxConcurrentAddons = 4
+
+// ProcedureType identifies the action a Procedure performs against a single
+// add-on. There is no separate "wait" procedure: Executor.Create/Update
+// block until the add-on reaches its terminal state themselves, so waiting
+// is folded into those two rather than being a step a caller schedules on
+// its own.
+type ProcedureType string
+
+const (
+	ProcedureCreate ProcedureType = "CreateAddon"
+	ProcedureUpdate ProcedureType = "UpdateAddon"
+	ProcedureDelete ProcedureType = "DeleteAddon"
+)
+
+// Procedure is a single step the plan intends to carry out for one add-on.
+type Procedure struct {
+	Type      ProcedureType
+	AddonName string
+	Desired   *eks.Addon
+	Current   *eks.Addon
+
+	// ResolveConflictsOnCreate is only consulted for ProcedureCreate; it's
+	// passed through to Executor.Create so a create that fails with a
+	// ConfigurationConflict health issue can be retried as an update with
+	// ResolveConflicts=OVERWRITE.
+	ResolveConflictsOnCreate string
+}
+
+// AddonPlan is the diff between the desired add-on set and the add-ons
+// currently attached to a cluster, expressed as a list of per-addon
+// Procedures.
+type AddonPlan struct {
+	ClusterName string
+	Procedures  []Procedure
+}
+
+// DesiredAddon is one entry of the caller's desired add-on set, paired with
+// the per-addon options that only apply at plan time (as opposed to options
+// like AddonVersion that live on the eks.Addon itself).
+type DesiredAddon struct {
+	Addon *eks.Addon
+
+	// ResolveConflictsOnCreate mirrors the `resolve_conflicts_on_create`
+	// schema attribute: "NONE", "OVERWRITE" or "PRESERVE" (empty defaults to
+	// the EKS API's own behavior).
+	ResolveConflictsOnCreate string
+}
+
+// NewPlan diffs desired against current (as returned by ListAddons) and
+// returns the set of procedures required to reconcile them.
+//
+// The desired slice is walked by index so each element is copied into its
+// own Procedure; ranging by value here would alias the loop variable across
+// iterations and silently reconcile only the last add-on in the list.
+func NewPlan(clusterName string, desired []*DesiredAddon, current []*eks.Addon) *AddonPlan {
+	currentByName := make(map[string]*eks.Addon, len(current))
+	for i := range current {
+		c := current[i]
+		currentByName[aws.StringValue(c.AddonName)] = c
+	}
+
+	desiredNames := make(map[string]struct{}, len(desired))
+
+	plan := &AddonPlan{ClusterName: clusterName}
+
+	for i := range desired {
+		a := desired[i]
+		name := aws.StringValue(a.Addon.AddonName)
+		desiredNames[name] = struct{}{}
+
+		if existing, ok := currentByName[name]; ok {
+			if addonsDiffer(a.Addon, existing) {
+				plan.Procedures = append(plan.Procedures, Procedure{
+					Type:      ProcedureUpdate,
+					AddonName: name,
+					Desired:   a.Addon,
+					Current:   existing,
+				})
+			}
+			continue
+		}
+
+		plan.Procedures = append(plan.Procedures, Procedure{
+			Type:                     ProcedureCreate,
+			AddonName:                name,
+			Desired:                  a.Addon,
+			ResolveConflictsOnCreate: a.ResolveConflictsOnCreate,
+		})
+	}
+
+	for i := range current {
+		c := current[i]
+		name := aws.StringValue(c.AddonName)
+		if _, ok := desiredNames[name]; ok {
+			continue
+		}
+
+		plan.Procedures = append(plan.Procedures, Procedure{
+			Type:      ProcedureDelete,
+			AddonName: name,
+			Current:   c,
+		})
+	}
+
+	return plan
+}
+
+// addonsDiffer reports whether desired specifies a change relative to
+// current, considering only the fields the caller can actually set
+// (AddonVersion, ServiceAccountRoleArn). An empty desired value means "no
+// opinion" and never triggers an update on its own.
+func addonsDiffer(desired, current *eks.Addon) bool {
+	if v := aws.StringValue(desired.AddonVersion); v != "" && v != aws.StringValue(current.AddonVersion) {
+		return true
+	}
+
+	if v := aws.StringValue(desired.ServiceAccountRoleArn); v != "" && v != aws.StringValue(current.ServiceAccountRoleArn) {
+		return true
+	}
+
+	return false
+}
+
+// Executor performs the AWS API calls backing a single Procedure and waits
+// for the resulting add-on state to settle.
+type Executor interface {
+	Create(ctx context.Context, clusterName string, desired *eks.Addon, resolveConflictsOnCreate string) (*eks.Addon, error)
+	Update(ctx context.Context, clusterName string, desired *eks.Addon) (*eks.Addon, error)
+	Delete(ctx context.Context, clusterName, addonName string) error
+}
+
+// addonResult carries the outcome of reconciling a single add-on back to the
+// caller over a per-addon state channel.
+type addonResult struct {
+	addonName string
+	addon     *eks.Addon
+	err       error
+}
+
+// Execute fans the plan's procedures out across a bounded worker pool,
+// running each add-on's create/update/delete and wait-for-active
+// independently so one slow add-on doesn't block the others. Per-addon
+// results are aggregated into a single error via multierror.
+func (p *AddonPlan) Execute(ctx context.Context, exec Executor) ([]*eks.Addon, error) {
+	results := make(chan addonResult, len(p.Procedures))
+	sem := make(chan struct{}, maxConcurrentAddons)
+
+	var wg sync.WaitGroup
+	for i := range p.Procedures {
+		proc := p.Procedures[i]
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			addon, err := execProcedure(ctx, exec, p.ClusterName, proc)
+			results <- addonResult{addonName: proc.AddonName, addon: addon, err: err}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var errs *multierror.Error
+	var reconciled []*eks.Addon
+	for result := range results {
+		if result.err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("%s: %w", result.addonName, result.err))
+			continue
+		}
+		if result.addon != nil {
+			reconciled = append(reconciled, result.addon)
+		}
+	}
+
+	return reconciled, errs.ErrorOrNil()
+}
+
+func execProcedure(ctx context.Context, exec Executor, clusterName string, proc Procedure) (*eks.Addon, error) {
+	switch proc.Type {
+	case ProcedureCreate:
+		return exec.Create(ctx, clusterName, proc.Desired, proc.ResolveConflictsOnCreate)
+	case ProcedureUpdate:
+		return exec.Update(ctx, clusterName, proc.Desired)
+	case ProcedureDelete:
+		return nil, exec.Delete(ctx, clusterName, proc.AddonName)
+	default:
+		return nil, fmt.Errorf("unsupported procedure type %q for add-on %s", proc.Type, proc.AddonName)
+	}
+}
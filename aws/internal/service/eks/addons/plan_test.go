@@ -0,0 +1,197 @@
+package addons
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eks"
+)
+
+func TestNewPlan(t *testing.T) {
+	testCases := []struct {
+		name      string
+		desired   []*DesiredAddon
+		current   []*eks.Addon
+		wantTypes map[string]ProcedureType
+	}{
+		{
+			name: "create when absent from current",
+			desired: []*DesiredAddon{
+				{Addon: &eks.Addon{AddonName: aws.String("vpc-cni")}},
+			},
+			wantTypes: map[string]ProcedureType{"vpc-cni": ProcedureCreate},
+		},
+		{
+			name: "no procedure when nothing changed",
+			desired: []*DesiredAddon{
+				{Addon: &eks.Addon{AddonName: aws.String("coredns"), AddonVersion: aws.String("v1.0")}},
+			},
+			current: []*eks.Addon{
+				{AddonName: aws.String("coredns"), AddonVersion: aws.String("v1.0")},
+			},
+			wantTypes: map[string]ProcedureType{},
+		},
+		{
+			name: "update when addon_version differs",
+			desired: []*DesiredAddon{
+				{Addon: &eks.Addon{AddonName: aws.String("coredns"), AddonVersion: aws.String("v2.0")}},
+			},
+			current: []*eks.Addon{
+				{AddonName: aws.String("coredns"), AddonVersion: aws.String("v1.0")},
+			},
+			wantTypes: map[string]ProcedureType{"coredns": ProcedureUpdate},
+		},
+		{
+			name: "update when service_account_role_arn differs",
+			desired: []*DesiredAddon{
+				{Addon: &eks.Addon{AddonName: aws.String("ebs-csi"), ServiceAccountRoleArn: aws.String("arn:aws:iam::1:role/new")}},
+			},
+			current: []*eks.Addon{
+				{AddonName: aws.String("ebs-csi"), ServiceAccountRoleArn: aws.String("arn:aws:iam::1:role/old")},
+			},
+			wantTypes: map[string]ProcedureType{"ebs-csi": ProcedureUpdate},
+		},
+		{
+			name:    "delete when absent from desired",
+			desired: nil,
+			current: []*eks.Addon{
+				{AddonName: aws.String("kube-proxy")},
+			},
+			wantTypes: map[string]ProcedureType{"kube-proxy": ProcedureDelete},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			plan := NewPlan("test-cluster", tc.desired, tc.current)
+
+			got := make(map[string]ProcedureType, len(plan.Procedures))
+			for _, p := range plan.Procedures {
+				got[p.AddonName] = p.Type
+			}
+
+			if len(got) != len(tc.wantTypes) {
+				t.Fatalf("got %d procedures, want %d: %+v", len(got), len(tc.wantTypes), plan.Procedures)
+			}
+
+			for name, wantType := range tc.wantTypes {
+				if got[name] != wantType {
+					t.Errorf("addon %s: got procedure %s, want %s", name, got[name], wantType)
+				}
+			}
+		})
+	}
+}
+
+func TestNewPlan_indexedLoopDoesNotAliasLastDesired(t *testing.T) {
+	desired := []*DesiredAddon{
+		{Addon: &eks.Addon{AddonName: aws.String("vpc-cni")}},
+		{Addon: &eks.Addon{AddonName: aws.String("coredns")}},
+		{Addon: &eks.Addon{AddonName: aws.String("kube-proxy")}},
+	}
+
+	plan := NewPlan("test-cluster", desired, nil)
+
+	if len(plan.Procedures) != len(desired) {
+		t.Fatalf("got %d procedures, want %d", len(plan.Procedures), len(desired))
+	}
+
+	names := make([]string, 0, len(plan.Procedures))
+	for _, p := range plan.Procedures {
+		names = append(names, p.AddonName)
+	}
+	sort.Strings(names)
+
+	want := []string{"coredns", "kube-proxy", "vpc-cni"}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("got addon names %v, want each of %v present", names, want)
+		}
+	}
+}
+
+// fakeExecutor records the calls it receives and returns canned responses,
+// keyed by addon name so a test can make a specific addon fail.
+type fakeExecutor struct {
+	mu     sync.Mutex
+	calls  []string
+	failOn map[string]error
+}
+
+func (f *fakeExecutor) record(addonName string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, addonName)
+}
+
+func (f *fakeExecutor) Create(ctx context.Context, clusterName string, desired *eks.Addon, resolveConflictsOnCreate string) (*eks.Addon, error) {
+	name := aws.StringValue(desired.AddonName)
+	f.record(name)
+	if err := f.failOn[name]; err != nil {
+		return nil, err
+	}
+	return desired, nil
+}
+
+func (f *fakeExecutor) Update(ctx context.Context, clusterName string, desired *eks.Addon) (*eks.Addon, error) {
+	name := aws.StringValue(desired.AddonName)
+	f.record(name)
+	if err := f.failOn[name]; err != nil {
+		return nil, err
+	}
+	return desired, nil
+}
+
+func (f *fakeExecutor) Delete(ctx context.Context, clusterName, addonName string) error {
+	f.record(addonName)
+	return f.failOn[addonName]
+}
+
+func TestAddonPlan_Execute(t *testing.T) {
+	plan := &AddonPlan{
+		ClusterName: "test-cluster",
+		Procedures: []Procedure{
+			{Type: ProcedureCreate, AddonName: "vpc-cni", Desired: &eks.Addon{AddonName: aws.String("vpc-cni")}},
+			{Type: ProcedureUpdate, AddonName: "coredns", Desired: &eks.Addon{AddonName: aws.String("coredns")}},
+			{Type: ProcedureDelete, AddonName: "kube-proxy"},
+		},
+	}
+
+	exec := &fakeExecutor{}
+	reconciled, err := plan.Execute(context.Background(), exec)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(reconciled) != 2 {
+		t.Fatalf("got %d reconciled add-ons, want 2 (delete has no return value): %+v", len(reconciled), reconciled)
+	}
+
+	if len(exec.calls) != len(plan.Procedures) {
+		t.Fatalf("got %d executor calls, want %d", len(exec.calls), len(plan.Procedures))
+	}
+}
+
+func TestAddonPlan_Execute_aggregatesErrors(t *testing.T) {
+	plan := &AddonPlan{
+		ClusterName: "test-cluster",
+		Procedures: []Procedure{
+			{Type: ProcedureCreate, AddonName: "vpc-cni", Desired: &eks.Addon{AddonName: aws.String("vpc-cni")}},
+			{Type: ProcedureUpdate, AddonName: "coredns", Desired: &eks.Addon{AddonName: aws.String("coredns")}},
+		},
+	}
+
+	exec := &fakeExecutor{failOn: map[string]error{"coredns": fmt.Errorf("boom")}}
+	reconciled, err := plan.Execute(context.Background(), exec)
+
+	if err == nil {
+		t.Fatal("expected an aggregated error, got nil")
+	}
+	if len(reconciled) != 1 {
+		t.Fatalf("got %d reconciled add-ons, want 1 (vpc-cni succeeded)", len(reconciled))
+	}
+}
@@ -0,0 +1,92 @@
+// Package errors holds typed errors for the EKS service package, used in
+// place of ad-hoc joined error strings so callers can branch on structured
+// fields instead of string-matching AWS error codes.
+package errors
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eks"
+)
+
+// AddonIssue is a single entry from an add-on's Health.Issues list.
+type AddonIssue struct {
+	Code        string
+	Message     string
+	ResourceIds []string
+}
+
+// AddonHealthError reports one or more add-on health issues surfaced while
+// waiting for an EKS add-on create or update to finish.
+type AddonHealthError struct {
+	ClusterName string
+	AddonName   string
+	issues      []AddonIssue
+}
+
+// NewAddonHealthError builds an AddonHealthError from the Health.Issues
+// returned on an eks.Addon.
+func NewAddonHealthError(clusterName, addonName string, issues []*eks.AddonIssue) *AddonHealthError {
+	err := &AddonHealthError{
+		ClusterName: clusterName,
+		AddonName:   addonName,
+	}
+
+	for _, issue := range issues {
+		err.issues = append(err.issues, AddonIssue{
+			Code:        aws.StringValue(issue.Code),
+			Message:     aws.StringValue(issue.Message),
+			ResourceIds: aws.StringValueSlice(issue.ResourceIds),
+		})
+	}
+
+	return err
+}
+
+// NewAddonHealthErrorFromUpdate builds an AddonHealthError from the Errors
+// list returned on a failed or cancelled eks.Update.
+func NewAddonHealthErrorFromUpdate(clusterName, addonName string, updateErrors []*eks.ErrorDetail) *AddonHealthError {
+	err := &AddonHealthError{
+		ClusterName: clusterName,
+		AddonName:   addonName,
+	}
+
+	for _, updateError := range updateErrors {
+		err.issues = append(err.issues, AddonIssue{
+			Code:        aws.StringValue(updateError.ErrorCode),
+			Message:     aws.StringValue(updateError.ErrorMessage),
+			ResourceIds: aws.StringValueSlice(updateError.ResourceIds),
+		})
+	}
+
+	return err
+}
+
+// Issues returns the structured health issues reported for the add-on.
+func (e *AddonHealthError) Issues() []AddonIssue {
+	return e.issues
+}
+
+func (e *AddonHealthError) Error() string {
+	var messages []string
+	for _, issue := range e.issues {
+		messages = append(messages, fmt.Sprintf("%s: %s (resources: %s)",
+			issue.Code, issue.Message, strings.Join(issue.ResourceIds, ", ")))
+	}
+
+	return fmt.Sprintf("EKS add-on (%s:%s) health issues:\n%s", e.ClusterName, e.AddonName, strings.Join(messages, "\n"))
+}
+
+// HasCode returns true if any reported issue matches the given AWS error
+// code, e.g. "InsufficientNumberOfReplicas" or "AdmissionRequestDenied".
+func (e *AddonHealthError) HasCode(code string) bool {
+	for _, issue := range e.issues {
+		if issue.Code == code {
+			return true
+		}
+	}
+
+	return false
+}
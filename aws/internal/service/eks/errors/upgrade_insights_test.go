@@ -0,0 +1,42 @@
+package errors
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUpgradeBlockedError_Findings(t *testing.T) {
+	findings := []UpgradeInsightFinding{
+		{InsightId: "insight-1", InsightName: "Deprecated API usage"},
+		{InsightId: "insight-2", InsightName: "Removed API usage"},
+	}
+
+	err := NewUpgradeBlockedError("my-cluster", "1.29", findings)
+
+	got := err.Findings()
+	if len(got) != len(findings) {
+		t.Fatalf("got %d findings, want %d", len(got), len(findings))
+	}
+	if got[0].InsightId != "insight-1" || got[1].InsightId != "insight-2" {
+		t.Errorf("got findings %+v, want them returned in order", got)
+	}
+}
+
+func TestUpgradeBlockedError_Error(t *testing.T) {
+	err := NewUpgradeBlockedError("my-cluster", "1.29", []UpgradeInsightFinding{
+		{
+			InsightName:         "Deprecated API usage",
+			Description:         "uses a removed API",
+			RecommendedGuidance: "migrate to the replacement API",
+			ResourceIds:         []string{"deployment/foo", "deployment/bar"},
+		},
+	})
+
+	msg := err.Error()
+
+	for _, want := range []string{"my-cluster", "1.29", "Deprecated API usage", "deployment/foo, deployment/bar", "migrate to the replacement API"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("Error() = %q, want it to contain %q", msg, want)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package waiter
+
+import (
+	"context"
+	"math/rand"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/aws-sdk-go-base/tfawserr"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// eksPollQPSEnvVar and eksPollBurstEnvVar let CI pipelines that run many
+	// EKS waiters in parallel dial polling pressure down without a code change.
+	eksPollQPSEnvVar   = "TF_AWS_EKS_POLL_QPS"
+	eksPollBurstEnvVar = "TF_AWS_EKS_POLL_BURST"
+
+	defaultEksPollQPS   = 5
+	defaultEksPollBurst = 10
+)
+
+// eksThrottledState is a synthetic Pending state used to keep a
+// StateChangeConf polling when the underlying Refresh call was throttled,
+// rather than surfacing ThrottlingException/RequestLimitExceeded as a hard
+// error.
+const eksThrottledState = "EKSThrottled"
+
+var (
+	eksPollLimiterOnce sync.Once
+	eksPollLimiter     *rate.Limiter
+)
+
+// sharedEksPollLimiter returns the package-level token-bucket limiter shared
+// across all EKS waiters, lazily built from TF_AWS_EKS_POLL_QPS /
+// TF_AWS_EKS_POLL_BURST (or their defaults) on first use.
+func sharedEksPollLimiter() *rate.Limiter {
+	eksPollLimiterOnce.Do(func() {
+		eksPollLimiter = rate.NewLimiter(rate.Limit(envFloat(eksPollQPSEnvVar, defaultEksPollQPS)), envInt(eksPollBurstEnvVar, defaultEksPollBurst))
+	})
+
+	return eksPollLimiter
+}
+
+func envFloat(name string, def float64) float64 {
+	v, err := strconv.ParseFloat(os.Getenv(name), 64)
+	if err != nil || v <= 0 {
+		return def
+	}
+
+	return v
+}
+
+func envInt(name string, def int) int {
+	v, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || v <= 0 {
+		return def
+	}
+
+	return v
+}
+
+// isEksThrottlingError returns true for the AWS error codes EKS returns when
+// a caller is polling too aggressively.
+func isEksThrottlingError(err error) bool {
+	return tfawserr.ErrCodeEquals(err, "ThrottlingException", "RequestLimitExceeded")
+}
+
+// throttleBackoff tracks consecutive throttling responses for a single
+// StateChangeConf so each one can be slowed down exponentially, with jitter,
+// instead of hammering the API at the configured PollInterval.
+type throttleBackoff struct {
+	attempts int
+}
+
+// maxThrottleBackoffAttempts caps the exponent used to compute the backoff
+// delay so a sustained throttling streak can't shift base past the point
+// where it overflows time.Duration (an int64) and goes negative.
+const maxThrottleBackoffAttempts = 6
+
+// nextDelay returns the base (unjittered) backoff delay for the current
+// attempt count and advances attempts, capping the exponent at
+// maxThrottleBackoffAttempts so a sustained throttling streak can't shift it
+// past the point where time.Duration overflows.
+func (b *throttleBackoff) nextDelay() time.Duration {
+	if b.attempts > maxThrottleBackoffAttempts {
+		b.attempts = maxThrottleBackoffAttempts
+	}
+
+	base := 500 * time.Millisecond
+	delay := base << b.attempts
+	if max := 30 * time.Second; delay > max {
+		delay = max
+	}
+	b.attempts++
+
+	return delay
+}
+
+// wait sleeps an exponentially increasing, jittered delay based on how many
+// throttle responses have been seen in a row. It does not reset the count;
+// rateLimitedRefresh calls reset once a poll comes back unthrottled.
+func (b *throttleBackoff) wait(ctx context.Context) {
+	delay := b.nextDelay()
+	jitter := time.Duration(rand.Int63n(int64(delay) / 2))
+
+	timer := time.NewTimer(delay/2 + jitter)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
+
+func (b *throttleBackoff) reset() {
+	b.attempts = 0
+}
+
+// rateLimitedRefresh wraps refresh so every poll first waits on the shared
+// EKS token-bucket limiter, and so a ThrottlingException/RequestLimitExceeded
+// response is treated as a non-terminal eksThrottledState (with an
+// exponential, jittered backoff) instead of a hard error.
+func rateLimitedRefresh(ctx context.Context, refresh resource.StateRefreshFunc) resource.StateRefreshFunc {
+	backoff := &throttleBackoff{}
+
+	return func() (interface{}, string, error) {
+		if err := sharedEksPollLimiter().Wait(ctx); err != nil {
+			return nil, "", err
+		}
+
+		result, state, err := refresh()
+		if err != nil && isEksThrottlingError(err) {
+			backoff.wait(ctx)
+			return result, eksThrottledState, nil
+		}
+
+		backoff.reset()
+		return result, state, err
+	}
+}
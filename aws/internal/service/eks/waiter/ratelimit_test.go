@@ -0,0 +1,70 @@
+package waiter
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestThrottleBackoff_nextDelay_growsExponentiallyAndCaps(t *testing.T) {
+	b := &throttleBackoff{}
+
+	var prev time.Duration
+	for i := 0; i < maxThrottleBackoffAttempts+3; i++ {
+		delay := b.nextDelay()
+
+		if i > 0 && i <= maxThrottleBackoffAttempts && delay <= prev {
+			t.Errorf("attempt %d: delay did not grow (got %s, previous %s)", i, delay, prev)
+		}
+		if max := 30 * time.Second; delay > max {
+			t.Errorf("attempt %d: delay %s exceeded cap %s", i, delay, max)
+		}
+		prev = delay
+	}
+
+	if b.attempts != maxThrottleBackoffAttempts+1 {
+		t.Errorf("got attempts %d, want exponent to stop advancing past %d", b.attempts, maxThrottleBackoffAttempts+1)
+	}
+}
+
+func TestThrottleBackoff_wait_respectsContextCancellation(t *testing.T) {
+	b := &throttleBackoff{attempts: maxThrottleBackoffAttempts}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	b.wait(ctx)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("wait did not return promptly on a cancelled context, took %s", elapsed)
+	}
+}
+
+func TestThrottleBackoff_reset(t *testing.T) {
+	b := &throttleBackoff{attempts: 4}
+	b.reset()
+
+	if b.attempts != 0 {
+		t.Errorf("got attempts %d after reset, want 0", b.attempts)
+	}
+}
+
+func TestEnvFloat(t *testing.T) {
+	t.Setenv("TF_AWS_EKS_POLL_QPS_TEST", "2.5")
+	if got := envFloat("TF_AWS_EKS_POLL_QPS_TEST", 5); got != 2.5 {
+		t.Errorf("got %v, want 2.5", got)
+	}
+	if got := envFloat("TF_AWS_EKS_POLL_QPS_TEST_UNSET", 5); got != 5 {
+		t.Errorf("got %v, want default 5", got)
+	}
+}
+
+func TestEnvInt(t *testing.T) {
+	t.Setenv("TF_AWS_EKS_POLL_BURST_TEST", "7")
+	if got := envInt("TF_AWS_EKS_POLL_BURST_TEST", 10); got != 7 {
+		t.Errorf("got %v, want 7", got)
+	}
+	if got := envInt("TF_AWS_EKS_POLL_BURST_TEST_UNSET", 10); got != 10 {
+		t.Errorf("got %v, want default 10", got)
+	}
+}
@@ -0,0 +1,86 @@
+package waiter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eks"
+	ekserr "github.com/terraform-providers/terraform-provider-aws/aws/internal/service/eks/errors"
+)
+
+const upgradeInsightsCategoryUpgradeReadiness = "UPGRADE_READINESS"
+
+// ClusterUpgradeReady is a preflight check, meant to run before
+// ClusterUpdateSuccessful as part of a cluster version update, that fails
+// fast with a structured error enumerating any UPGRADE_READINESS insight
+// findings (deprecated API usage, etc.) that AWS reports as blocking for the
+// given target Kubernetes version, rather than letting the control plane
+// upgrade itself fail partway through.
+//
+// This codebase slice has no aws_eks_cluster resource (and so no
+// resourceAwsEksClusterUpdate) to call this from; it is exported and tested
+// in isolation so the eventual cluster-update code path can wire it in
+// without reimplementing the ListInsights/DescribeInsight handling.
+func ClusterUpgradeReady(ctx context.Context, conn *eks.EKS, name, targetVersion string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var insightIds []string
+
+	err := conn.ListInsightsPagesWithContext(ctx, &eks.ListInsightsInput{
+		ClusterName: aws.String(name),
+		Filter: &eks.InsightsFilter{
+			Categories:         aws.StringSlice([]string{upgradeInsightsCategoryUpgradeReadiness}),
+			KubernetesVersions: aws.StringSlice([]string{targetVersion}),
+		},
+	}, func(page *eks.ListInsightsOutput, lastPage bool) bool {
+		for _, insight := range page.Insights {
+			if insight.InsightStatus != nil && aws.StringValue(insight.InsightStatus.Status) == eks.InsightStatusValueError {
+				insightIds = append(insightIds, aws.StringValue(insight.Id))
+			}
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return fmt.Errorf("error listing EKS upgrade insights for cluster (%s) target version (%s): %w", name, targetVersion, err)
+	}
+
+	if len(insightIds) == 0 {
+		return nil
+	}
+
+	findings := make([]ekserr.UpgradeInsightFinding, 0, len(insightIds))
+	for _, id := range insightIds {
+		output, err := conn.DescribeInsightWithContext(ctx, &eks.DescribeInsightInput{
+			ClusterName: aws.String(name),
+			Id:          aws.String(id),
+		})
+		if err != nil {
+			return fmt.Errorf("error describing EKS upgrade insight (%s) for cluster (%s): %w", id, name, err)
+		}
+
+		findings = append(findings, upgradeInsightFindingFromDescribe(output.Insight))
+	}
+
+	return ekserr.NewUpgradeBlockedError(name, targetVersion, findings)
+}
+
+func upgradeInsightFindingFromDescribe(insight *eks.InsightDetail) ekserr.UpgradeInsightFinding {
+	var resourceIds []string
+	for _, detail := range insight.ResourceDetails {
+		if detail.InsightResourceDetail != nil {
+			resourceIds = append(resourceIds, aws.StringValue(detail.InsightResourceDetail.ResourceArn))
+		}
+	}
+
+	return ekserr.UpgradeInsightFinding{
+		InsightId:           aws.StringValue(insight.Id),
+		InsightName:         aws.StringValue(insight.Name),
+		KubernetesVersion:   aws.StringValue(insight.KubernetesVersion),
+		Description:         aws.StringValue(insight.Description),
+		RecommendedGuidance: aws.StringValue(insight.RecommendedResolution),
+		ResourceIds:         resourceIds,
+	}
+}
@@ -0,0 +1,58 @@
+package waiter
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eks"
+)
+
+func TestUpgradeInsightFindingFromDescribe(t *testing.T) {
+	insight := &eks.InsightDetail{
+		Id:                    aws.String("insight-1"),
+		Name:                  aws.String("Deprecated API usage"),
+		KubernetesVersion:     aws.String("1.29"),
+		Description:           aws.String("uses a removed API"),
+		RecommendedResolution: aws.String("migrate to the replacement API"),
+		ResourceDetails: []*eks.ResourceDetail{
+			{
+				InsightResourceDetail: &eks.InsightResourceDetail{
+					ResourceArn: aws.String("arn:aws:apps/v1/deployments/foo"),
+				},
+			},
+			{
+				InsightResourceDetail: &eks.InsightResourceDetail{
+					ResourceArn: aws.String("arn:aws:apps/v1/deployments/bar"),
+				},
+			},
+		},
+	}
+
+	got := upgradeInsightFindingFromDescribe(insight)
+
+	if got.InsightId != "insight-1" || got.InsightName != "Deprecated API usage" || got.KubernetesVersion != "1.29" {
+		t.Fatalf("got %+v, want fields copied from insight", got)
+	}
+	if got.Description != "uses a removed API" || got.RecommendedGuidance != "migrate to the replacement API" {
+		t.Fatalf("got %+v, want description/guidance copied from insight", got)
+	}
+	if len(got.ResourceIds) != 2 || got.ResourceIds[0] != "arn:aws:apps/v1/deployments/foo" || got.ResourceIds[1] != "arn:aws:apps/v1/deployments/bar" {
+		t.Fatalf("got resource IDs %v, want both ARNs in order", got.ResourceIds)
+	}
+}
+
+func TestUpgradeInsightFindingFromDescribe_nilResourceDetail(t *testing.T) {
+	insight := &eks.InsightDetail{
+		Id:   aws.String("insight-2"),
+		Name: aws.String("No affected resources"),
+		ResourceDetails: []*eks.ResourceDetail{
+			{},
+		},
+	}
+
+	got := upgradeInsightFindingFromDescribe(insight)
+
+	if len(got.ResourceIds) != 0 {
+		t.Fatalf("got resource IDs %v, want none when InsightResourceDetail is nil", got.ResourceIds)
+	}
+}
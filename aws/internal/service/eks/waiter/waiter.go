@@ -3,7 +3,6 @@ package waiter
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -11,6 +10,8 @@ import (
 	"github.com/hashicorp/aws-sdk-go-base/tfawserr"
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/terraform-providers/terraform-provider-aws/aws/internal/service/eks/addons"
+	ekserr "github.com/terraform-providers/terraform-provider-aws/aws/internal/service/eks/errors"
 	"github.com/terraform-providers/terraform-provider-aws/aws/internal/tfresource"
 )
 
@@ -20,12 +21,25 @@ const (
 	EksAddonDeletedTimeout = 40 * time.Minute
 )
 
+// Polling cadence shared by every StateChangeConf in this package. Delay
+// gives the API a moment to reflect the change that kicked off the wait;
+// MinTimeout/PollInterval bound how aggressively we re-poll on top of
+// whatever backoff rateLimitedRefresh adds when throttled.
+const (
+	eksPollDelay      = 10 * time.Second
+	eksPollMinTimeout = 3 * time.Second
+	eksPollInterval   = 10 * time.Second
+)
+
 func ClusterCreated(conn *eks.EKS, name string, timeout time.Duration) (*eks.Cluster, error) {
 	stateConf := &resource.StateChangeConf{
-		Pending: []string{eks.ClusterStatusCreating},
-		Target:  []string{eks.ClusterStatusActive},
-		Refresh: ClusterStatus(conn, name),
-		Timeout: timeout,
+		Pending:      []string{eks.ClusterStatusCreating, eksThrottledState},
+		Target:       []string{eks.ClusterStatusActive},
+		Refresh:      rateLimitedRefresh(context.Background(), ClusterStatus(conn, name)),
+		Timeout:      timeout,
+		Delay:        eksPollDelay,
+		MinTimeout:   eksPollMinTimeout,
+		PollInterval: eksPollInterval,
 	}
 
 	outputRaw, err := stateConf.WaitForState()
@@ -39,10 +53,13 @@ func ClusterCreated(conn *eks.EKS, name string, timeout time.Duration) (*eks.Clu
 
 func ClusterDeleted(conn *eks.EKS, name string, timeout time.Duration) (*eks.Cluster, error) {
 	stateConf := &resource.StateChangeConf{
-		Pending: []string{eks.ClusterStatusActive, eks.ClusterStatusDeleting},
-		Target:  []string{},
-		Refresh: ClusterStatus(conn, name),
-		Timeout: timeout,
+		Pending:      []string{eks.ClusterStatusActive, eks.ClusterStatusDeleting, eksThrottledState},
+		Target:       []string{},
+		Refresh:      rateLimitedRefresh(context.Background(), ClusterStatus(conn, name)),
+		Timeout:      timeout,
+		Delay:        eksPollDelay,
+		MinTimeout:   eksPollMinTimeout,
+		PollInterval: eksPollInterval,
 	}
 
 	outputRaw, err := stateConf.WaitForState()
@@ -56,10 +73,13 @@ func ClusterDeleted(conn *eks.EKS, name string, timeout time.Duration) (*eks.Clu
 
 func ClusterUpdateSuccessful(conn *eks.EKS, name, id string, timeout time.Duration) (*eks.Update, error) {
 	stateConf := &resource.StateChangeConf{
-		Pending: []string{eks.UpdateStatusInProgress},
-		Target:  []string{eks.UpdateStatusSuccessful},
-		Refresh: ClusterUpdateStatus(conn, name, id),
-		Timeout: timeout,
+		Pending:      []string{eks.UpdateStatusInProgress, eksThrottledState},
+		Target:       []string{eks.UpdateStatusSuccessful},
+		Refresh:      rateLimitedRefresh(context.Background(), ClusterUpdateStatus(conn, name, id)),
+		Timeout:      timeout,
+		Delay:        eksPollDelay,
+		MinTimeout:   eksPollMinTimeout,
+		PollInterval: eksPollInterval,
 	}
 
 	outputRaw, err := stateConf.WaitForState()
@@ -82,10 +102,13 @@ func ClusterUpdateSuccessful(conn *eks.EKS, name, id string, timeout time.Durati
 
 func FargateProfileCreated(conn *eks.EKS, clusterName, fargateProfileName string, timeout time.Duration) (*eks.FargateProfile, error) {
 	stateConf := &resource.StateChangeConf{
-		Pending: []string{eks.FargateProfileStatusCreating},
-		Target:  []string{eks.FargateProfileStatusActive},
-		Refresh: FargateProfileStatus(conn, clusterName, fargateProfileName),
-		Timeout: timeout,
+		Pending:      []string{eks.FargateProfileStatusCreating, eksThrottledState},
+		Target:       []string{eks.FargateProfileStatusActive},
+		Refresh:      rateLimitedRefresh(context.Background(), FargateProfileStatus(conn, clusterName, fargateProfileName)),
+		Timeout:      timeout,
+		Delay:        eksPollDelay,
+		MinTimeout:   eksPollMinTimeout,
+		PollInterval: eksPollInterval,
 	}
 
 	outputRaw, err := stateConf.WaitForState()
@@ -99,10 +122,13 @@ func FargateProfileCreated(conn *eks.EKS, clusterName, fargateProfileName string
 
 func FargateProfileDeleted(conn *eks.EKS, clusterName, fargateProfileName string, timeout time.Duration) (*eks.FargateProfile, error) {
 	stateConf := &resource.StateChangeConf{
-		Pending: []string{eks.FargateProfileStatusActive, eks.FargateProfileStatusDeleting},
-		Target:  []string{},
-		Refresh: FargateProfileStatus(conn, clusterName, fargateProfileName),
-		Timeout: timeout,
+		Pending:      []string{eks.FargateProfileStatusActive, eks.FargateProfileStatusDeleting, eksThrottledState},
+		Target:       []string{},
+		Refresh:      rateLimitedRefresh(context.Background(), FargateProfileStatus(conn, clusterName, fargateProfileName)),
+		Timeout:      timeout,
+		Delay:        eksPollDelay,
+		MinTimeout:   eksPollMinTimeout,
+		PollInterval: eksPollInterval,
 	}
 
 	outputRaw, err := stateConf.WaitForState()
@@ -116,10 +142,13 @@ func FargateProfileDeleted(conn *eks.EKS, clusterName, fargateProfileName string
 
 func NodegroupCreated(conn *eks.EKS, clusterName, nodeGroupName string, timeout time.Duration) (*eks.Nodegroup, error) {
 	stateConf := &resource.StateChangeConf{
-		Pending: []string{eks.NodegroupStatusCreating},
-		Target:  []string{eks.NodegroupStatusActive},
-		Refresh: NodegroupStatus(conn, clusterName, nodeGroupName),
-		Timeout: timeout,
+		Pending:      []string{eks.NodegroupStatusCreating, eksThrottledState},
+		Target:       []string{eks.NodegroupStatusActive},
+		Refresh:      rateLimitedRefresh(context.Background(), NodegroupStatus(conn, clusterName, nodeGroupName)),
+		Timeout:      timeout,
+		Delay:        eksPollDelay,
+		MinTimeout:   eksPollMinTimeout,
+		PollInterval: eksPollInterval,
 	}
 
 	outputRaw, err := stateConf.WaitForState()
@@ -133,10 +162,13 @@ func NodegroupCreated(conn *eks.EKS, clusterName, nodeGroupName string, timeout
 
 func NodegroupDeleted(conn *eks.EKS, clusterName, nodeGroupName string, timeout time.Duration) (*eks.Nodegroup, error) {
 	stateConf := &resource.StateChangeConf{
-		Pending: []string{eks.NodegroupStatusActive, eks.NodegroupStatusDeleting},
-		Target:  []string{},
-		Refresh: NodegroupStatus(conn, clusterName, nodeGroupName),
-		Timeout: timeout,
+		Pending:      []string{eks.NodegroupStatusActive, eks.NodegroupStatusDeleting, eksThrottledState},
+		Target:       []string{},
+		Refresh:      rateLimitedRefresh(context.Background(), NodegroupStatus(conn, clusterName, nodeGroupName)),
+		Timeout:      timeout,
+		Delay:        eksPollDelay,
+		MinTimeout:   eksPollMinTimeout,
+		PollInterval: eksPollInterval,
 	}
 
 	outputRaw, err := stateConf.WaitForState()
@@ -150,10 +182,13 @@ func NodegroupDeleted(conn *eks.EKS, clusterName, nodeGroupName string, timeout
 
 func NodegroupUpdateSuccessful(conn *eks.EKS, clusterName, nodeGroupName, id string, timeout time.Duration) (*eks.Update, error) {
 	stateConf := &resource.StateChangeConf{
-		Pending: []string{eks.UpdateStatusInProgress},
-		Target:  []string{eks.UpdateStatusSuccessful},
-		Refresh: NodegroupUpdateStatus(conn, clusterName, nodeGroupName, id),
-		Timeout: timeout,
+		Pending:      []string{eks.UpdateStatusInProgress, eksThrottledState},
+		Target:       []string{eks.UpdateStatusSuccessful},
+		Refresh:      rateLimitedRefresh(context.Background(), NodegroupUpdateStatus(conn, clusterName, nodeGroupName, id)),
+		Timeout:      timeout,
+		Delay:        eksPollDelay,
+		MinTimeout:   eksPollMinTimeout,
+		PollInterval: eksPollInterval,
 	}
 
 	outputRaw, err := stateConf.WaitForState()
@@ -174,37 +209,102 @@ func NodegroupUpdateSuccessful(conn *eks.EKS, clusterName, nodeGroupName, id str
 	return nil, err
 }
 
-// EksAddonCreated waits for a EKS add-on to return status "ACTIVE" or "CREATE_FAILED"
-func EksAddonCreated(ctx context.Context, conn *eks.EKS, clusterName, addonName string) (*eks.Addon, error) {
+// maxConflictResolutionAttempts bounds how many times EksAddonCreated will
+// retry a ConfigurationConflict create failure as an OVERWRITE update before
+// giving up.
+const maxConflictResolutionAttempts = 2
+
+// configurationConflictCode is the Health.Issues code EKS reports when an
+// add-on create fails because the CRDs/ConfigMaps it manages already exist
+// on the cluster (e.g. migrating self-managed coredns to a managed add-on).
+const configurationConflictCode = "ConfigurationConflict"
+
+// EksAddonCreated waits for a EKS add-on to return status "ACTIVE" or "CREATE_FAILED".
+//
+// If the create fails with a ConfigurationConflict health issue and
+// resolveConflicts is eks.ResolveConflictsOverwrite, it automatically issues
+// an UpdateAddon with ResolveConflicts=OVERWRITE and resumes waiting for
+// "ACTIVE", retrying up to maxConflictResolutionAttempts times before
+// returning the full error chain.
+//
+// Unlike ClusterUpdateSuccessful/NodegroupUpdateSuccessful above, this
+// doesn't route through tfresource.SetLastError: "CREATE_FAILED" is one of
+// this wait's own Target states, so WaitForStateContext already returns a
+// nil error on that path and there's never a *resource.TimeoutError for
+// SetLastError to annotate. The health error is returned directly instead.
+func EksAddonCreated(ctx context.Context, conn *eks.EKS, clusterName, addonName, resolveConflicts string) (*eks.Addon, error) {
+	return eksAddonCreated(ctx, conn, clusterName, addonName, resolveConflicts, 0)
+}
+
+func eksAddonCreated(ctx context.Context, conn *eks.EKS, clusterName, addonName, resolveConflicts string, attempt int) (*eks.Addon, error) {
 	stateConf := resource.StateChangeConf{
-		Pending: []string{eks.AddonStatusCreating},
+		Pending: []string{eks.AddonStatusCreating, eksThrottledState},
 		Target: []string{
 			eks.AddonStatusActive,
 			eks.AddonStatusCreateFailed,
 		},
-		Refresh: EksAddonStatus(ctx, conn, addonName, clusterName),
-		Timeout: EksAddonCreatedTimeout,
+		Refresh:      rateLimitedRefresh(ctx, EksAddonStatus(ctx, conn, addonName, clusterName)),
+		Timeout:      EksAddonCreatedTimeout,
+		Delay:        eksPollDelay,
+		MinTimeout:   eksPollMinTimeout,
+		PollInterval: eksPollInterval,
 	}
 
 	outputRaw, err := stateConf.WaitForStateContext(ctx)
 
-	if addon, ok := outputRaw.(*eks.Addon); ok {
-		// If "CREATE_FAILED" status was returned, gather add-on health issues and return error
-		if aws.StringValue(addon.Status) == eks.AddonStatusCreateFailed {
-			var detailedErrors []string
-			for i, addonIssue := range addon.Health.Issues {
-				detailedErrors = append(detailedErrors, fmt.Sprintf("Error %d: Code: %s / Message: %s",
-					i+1, aws.StringValue(addonIssue.Code), aws.StringValue(addonIssue.Message)))
-			}
+	addon, ok := outputRaw.(*eks.Addon)
+	if !ok {
+		return nil, err
+	}
 
-			return addon, fmt.Errorf("creation not successful (%s): Errors:\n%s",
-				aws.StringValue(addon.Status), strings.Join(detailedErrors, "\n"))
+	// If "CREATE_FAILED" status was returned, gather add-on health issues and return a
+	// structured error so callers can branch on specific issue codes instead of
+	// string-matching.
+	if aws.StringValue(addon.Status) != eks.AddonStatusCreateFailed {
+		return addon, err
+	}
+
+	healthErr := ekserr.NewAddonHealthError(clusterName, addonName, addon.Health.Issues)
+
+	if resolveConflicts == eks.ResolveConflictsOverwrite && healthErr.HasCode(configurationConflictCode) && attempt < maxConflictResolutionAttempts {
+		resumed, resumeErr := eksAddonResolveConflictAndWait(ctx, conn, clusterName, addonName)
+		if resumeErr == nil {
+			return resumed, nil
 		}
 
-		return addon, err
+		var errs *multierror.Error
+		errs = multierror.Append(errs, healthErr, resumeErr)
+
+		retried, retryErr := eksAddonCreated(ctx, conn, clusterName, addonName, resolveConflicts, attempt+1)
+		if retryErr == nil {
+			return retried, nil
+		}
+
+		errs = multierror.Append(errs, retryErr)
+		return addon, errs.ErrorOrNil()
 	}
 
-	return nil, err
+	return addon, healthErr
+}
+
+// eksAddonResolveConflictAndWait issues the OVERWRITE update that resolves a
+// ConfigurationConflict create failure and waits for the add-on to reach
+// "ACTIVE".
+func eksAddonResolveConflictAndWait(ctx context.Context, conn *eks.EKS, clusterName, addonName string) (*eks.Addon, error) {
+	output, err := conn.UpdateAddonWithContext(ctx, &eks.UpdateAddonInput{
+		AddonName:        aws.String(addonName),
+		ClusterName:      aws.String(clusterName),
+		ResolveConflicts: aws.String(eks.ResolveConflictsOverwrite),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error resolving configuration conflict for EKS add-on (%s:%s): %w", clusterName, addonName, err)
+	}
+
+	if _, err := EksAddonUpdateSuccessful(ctx, conn, clusterName, addonName, aws.StringValue(output.Update.Id)); err != nil {
+		return nil, err
+	}
+
+	return EksAddonStatusAddon(ctx, conn, clusterName, addonName)
 }
 
 // EksAddonDeleted waits for a EKS add-on to be deleted
@@ -213,10 +313,14 @@ func EksAddonDeleted(ctx context.Context, conn *eks.EKS, clusterName, addonName
 		Pending: []string{
 			eks.AddonStatusActive,
 			eks.AddonStatusDeleting,
+			eksThrottledState,
 		},
-		Target:  []string{},
-		Refresh: EksAddonStatus(ctx, conn, addonName, clusterName),
-		Timeout: EksAddonDeletedTimeout,
+		Target:       []string{},
+		Refresh:      rateLimitedRefresh(ctx, EksAddonStatus(ctx, conn, addonName, clusterName)),
+		Timeout:      EksAddonDeletedTimeout,
+		Delay:        eksPollDelay,
+		MinTimeout:   eksPollMinTimeout,
+		PollInterval: eksPollInterval,
 	}
 
 	outputRaw, err := stateConf.WaitForStateContext(ctx)
@@ -234,17 +338,25 @@ func EksAddonDeleted(ctx context.Context, conn *eks.EKS, clusterName, addonName
 	return nil, err
 }
 
-// EksAddonUpdateSuccessful waits for a EKS add-on update to return "Successful"
+// EksAddonUpdateSuccessful waits for a EKS add-on update to return "Successful".
+//
+// Like EksAddonCreated, this returns its structured health error directly
+// rather than through tfresource.SetLastError: "Cancelled"/"Failed" are
+// Target states here too, so err is nil by the time there's a health error
+// to attach.
 func EksAddonUpdateSuccessful(ctx context.Context, conn *eks.EKS, clusterName, addonName, updateID string) (*eks.Update, error) {
 	stateConf := resource.StateChangeConf{
-		Pending: []string{eks.UpdateStatusInProgress},
+		Pending: []string{eks.UpdateStatusInProgress, eksThrottledState},
 		Target: []string{
 			eks.UpdateStatusCancelled,
 			eks.UpdateStatusFailed,
 			eks.UpdateStatusSuccessful,
 		},
-		Refresh: EksAddonUpdateStatus(ctx, conn, clusterName, addonName, updateID),
-		Timeout: EksAddonUpdatedTimeout,
+		Refresh:      rateLimitedRefresh(ctx, EksAddonUpdateStatus(ctx, conn, clusterName, addonName, updateID)),
+		Timeout:      EksAddonUpdatedTimeout,
+		Delay:        eksPollDelay,
+		MinTimeout:   eksPollMinTimeout,
+		PollInterval: eksPollInterval,
 	}
 
 	outputRaw, err := stateConf.WaitForStateContext(ctx)
@@ -261,12 +373,124 @@ func EksAddonUpdateSuccessful(ctx context.Context, conn *eks.EKS, clusterName, a
 		return nil, nil
 	}
 
-	var detailedErrors []string
-	for i, updateError := range update.Errors {
-		detailedErrors = append(detailedErrors, fmt.Sprintf("Error %d: Code: %s / Message: %s",
-			i+1, aws.StringValue(updateError.ErrorCode), aws.StringValue(updateError.ErrorMessage)))
+	healthErr := ekserr.NewAddonHealthErrorFromUpdate(clusterName, addonName, update.Errors)
+
+	return update, healthErr
+}
+
+// eksAddonPlanExecutor implements addons.Executor on top of a real EKS
+// connection, reusing the single-addon waiters above to block each
+// create/update until the add-on reaches a terminal state.
+type eksAddonPlanExecutor struct {
+	conn *eks.EKS
+}
+
+func (e *eksAddonPlanExecutor) Create(ctx context.Context, clusterName string, desired *eks.Addon, resolveConflictsOnCreate string) (*eks.Addon, error) {
+	input := &eks.CreateAddonInput{
+		AddonName:             desired.AddonName,
+		AddonVersion:          desired.AddonVersion,
+		ClusterName:           aws.String(clusterName),
+		ServiceAccountRoleArn: desired.ServiceAccountRoleArn,
+	}
+
+	if resolveConflictsOnCreate != "" {
+		input.ResolveConflicts = aws.String(resolveConflictsOnCreate)
+	}
+
+	if _, err := e.conn.CreateAddonWithContext(ctx, input); err != nil {
+		return nil, fmt.Errorf("error creating EKS add-on (%s:%s): %w", clusterName, aws.StringValue(desired.AddonName), err)
+	}
+
+	return EksAddonCreated(ctx, e.conn, clusterName, aws.StringValue(desired.AddonName), resolveConflictsOnCreate)
+}
+
+func (e *eksAddonPlanExecutor) Update(ctx context.Context, clusterName string, desired *eks.Addon) (*eks.Addon, error) {
+	addonName := aws.StringValue(desired.AddonName)
+
+	input := &eks.UpdateAddonInput{
+		AddonName:             desired.AddonName,
+		AddonVersion:          desired.AddonVersion,
+		ClusterName:           aws.String(clusterName),
+		ServiceAccountRoleArn: desired.ServiceAccountRoleArn,
+	}
+
+	output, err := e.conn.UpdateAddonWithContext(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("error updating EKS add-on (%s:%s): %w", clusterName, addonName, err)
+	}
+
+	if _, err := EksAddonUpdateSuccessful(ctx, e.conn, clusterName, addonName, aws.StringValue(output.Update.Id)); err != nil {
+		return nil, err
+	}
+
+	return EksAddonStatusAddon(ctx, e.conn, clusterName, addonName)
+}
+
+func (e *eksAddonPlanExecutor) Delete(ctx context.Context, clusterName, addonName string) error {
+	_, err := e.conn.DeleteAddonWithContext(ctx, &eks.DeleteAddonInput{
+		AddonName:   aws.String(addonName),
+		ClusterName: aws.String(clusterName),
+	})
+	if err != nil && !tfawserr.ErrCodeEquals(err, eks.ErrCodeResourceNotFoundException) {
+		return fmt.Errorf("error deleting EKS add-on (%s:%s): %w", clusterName, addonName, err)
+	}
+
+	_, err = EksAddonDeleted(ctx, e.conn, clusterName, addonName)
+	return err
+}
+
+// EksAddonsReconciled diffs the desired add-on set against the add-ons
+// currently attached to the cluster and drives create/update/delete for all
+// of them concurrently, returning the resulting add-ons once every
+// procedure has reached a terminal state.
+func EksAddonsReconciled(ctx context.Context, conn *eks.EKS, clusterName string, desired []*addons.DesiredAddon) ([]*eks.Addon, error) {
+	current, err := listEksAddons(ctx, conn, clusterName)
+	if err != nil {
+		return nil, fmt.Errorf("error listing EKS add-ons for cluster (%s): %w", clusterName, err)
+	}
+
+	plan := addons.NewPlan(clusterName, desired, current)
+
+	return plan.Execute(ctx, &eksAddonPlanExecutor{conn: conn})
+}
+
+// listEksAddons returns the current state of every add-on attached to the
+// cluster, to be diffed against the caller's desired add-on set.
+func listEksAddons(ctx context.Context, conn *eks.EKS, clusterName string) ([]*eks.Addon, error) {
+	var names []string
+
+	err := conn.ListAddonsPagesWithContext(ctx, &eks.ListAddonsInput{
+		ClusterName: aws.String(clusterName),
+	}, func(page *eks.ListAddonsOutput, lastPage bool) bool {
+		names = append(names, aws.StringValueSlice(page.Addons)...)
+		return !lastPage
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	current := make([]*eks.Addon, 0, len(names))
+	for _, name := range names {
+		addon, err := EksAddonStatusAddon(ctx, conn, clusterName, name)
+		if err != nil {
+			return nil, fmt.Errorf("error describing EKS add-on (%s:%s): %w", clusterName, name, err)
+		}
+		current = append(current, addon)
+	}
+
+	return current, nil
+}
+
+// EksAddonStatusAddon fetches the current state of a single add-on, for use
+// once a create/update has already reached a terminal status.
+func EksAddonStatusAddon(ctx context.Context, conn *eks.EKS, clusterName, addonName string) (*eks.Addon, error) {
+	output, err := conn.DescribeAddonWithContext(ctx, &eks.DescribeAddonInput{
+		AddonName:   aws.String(addonName),
+		ClusterName: aws.String(clusterName),
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return update, fmt.Errorf("EKS add-on (%s:%s) update (%s) not successful (%s): Errors:\n%s",
-		clusterName, addonName, updateID, aws.StringValue(update.Status), strings.Join(detailedErrors, "\n"))
+	return output.Addon, nil
 }
@@ -0,0 +1,255 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eks"
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/terraform-providers/terraform-provider-aws/aws/internal/service/eks/addons"
+	ekserr "github.com/terraform-providers/terraform-provider-aws/aws/internal/service/eks/errors"
+	"github.com/terraform-providers/terraform-provider-aws/aws/internal/service/eks/waiter"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func resourceAwsEksAddons() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAwsEksAddonsCreate,
+		ReadContext:   resourceAwsEksAddonsRead,
+		UpdateContext: resourceAwsEksAddonsCreate,
+		DeleteContext: resourceAwsEksAddonsDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(40 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"cluster_name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringLenBetween(1, 100),
+			},
+			"addon": {
+				Type:     schema.TypeSet,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"addon_name": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringLenBetween(1, 100),
+						},
+						"addon_version": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+						},
+						"service_account_role_arn": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.NoZeroValues,
+						},
+						"resolve_conflicts_on_create": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ValidateFunc: validation.StringInSlice([]string{
+								eks.ResolveConflictsNone,
+								eks.ResolveConflictsOverwrite,
+								eks.ResolveConflictsPreserve,
+							}, false),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceAwsEksAddonsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*AWSClient).eksconn
+	clusterName := d.Get("cluster_name").(string)
+
+	desired, err := expandEksAddonsSet(d.Get("addon").(*schema.Set))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	reconciled, err := waiter.EksAddonsReconciled(ctx, conn, clusterName, desired)
+	if err != nil {
+		if diags := diagnosticsFromAddonHealthErrors(err); len(diags) > 0 {
+			return diags
+		}
+
+		return diag.Errorf("error reconciling EKS add-ons for cluster (%s): %s", clusterName, err)
+	}
+
+	log.Printf("[DEBUG] Reconciled %d EKS add-on(s) for cluster %s", len(reconciled), clusterName)
+
+	d.SetId(clusterName)
+
+	return resourceAwsEksAddonsRead(ctx, d, meta)
+}
+
+func resourceAwsEksAddonsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*AWSClient).eksconn
+	clusterName := d.Id()
+
+	var addonNames []string
+	err := conn.ListAddonsPagesWithContext(ctx, &eks.ListAddonsInput{
+		ClusterName: aws.String(clusterName),
+	}, func(page *eks.ListAddonsOutput, lastPage bool) bool {
+		addonNames = append(addonNames, aws.StringValueSlice(page.Addons)...)
+		return !lastPage
+	})
+	if err != nil {
+		return diag.Errorf("error listing EKS add-ons for cluster (%s): %s", clusterName, err)
+	}
+
+	if len(addonNames) == 0 {
+		log.Printf("[WARN] No EKS add-ons found for cluster %s, removing from state", clusterName)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("cluster_name", clusterName)
+
+	// resolve_conflicts_on_create is a one-time creation directive, not a
+	// property EKS reports back via DescribeAddon, so it has to be carried
+	// forward from the prior state rather than refreshed from the API.
+	resolveConflictsByName := make(map[string]string)
+	for _, raw := range d.Get("addon").(*schema.Set).List() {
+		tfMap, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		name, _ := tfMap["addon_name"].(string)
+		resolveConflicts, _ := tfMap["resolve_conflicts_on_create"].(string)
+		if name != "" && resolveConflicts != "" {
+			resolveConflictsByName[name] = resolveConflicts
+		}
+	}
+
+	addonList := make([]map[string]interface{}, 0, len(addonNames))
+	for _, name := range addonNames {
+		output, err := conn.DescribeAddonWithContext(ctx, &eks.DescribeAddonInput{
+			AddonName:   aws.String(name),
+			ClusterName: aws.String(clusterName),
+		})
+		if err != nil {
+			return diag.Errorf("error describing EKS add-on (%s:%s): %s", clusterName, name, err)
+		}
+
+		addonList = append(addonList, map[string]interface{}{
+			"addon_name":                  aws.StringValue(output.Addon.AddonName),
+			"addon_version":               aws.StringValue(output.Addon.AddonVersion),
+			"service_account_role_arn":    aws.StringValue(output.Addon.ServiceAccountRoleArn),
+			"resolve_conflicts_on_create": resolveConflictsByName[name],
+		})
+	}
+
+	if err := d.Set("addon", addonList); err != nil {
+		return diag.Errorf("error setting addon: %s", err)
+	}
+
+	return nil
+}
+
+func resourceAwsEksAddonsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*AWSClient).eksconn
+	clusterName := d.Id()
+
+	if _, err := waiter.EksAddonsReconciled(ctx, conn, clusterName, nil); err != nil {
+		return diag.Errorf("error removing EKS add-ons for cluster (%s): %s", clusterName, err)
+	}
+
+	return nil
+}
+
+func expandEksAddonsSet(set *schema.Set) ([]*addons.DesiredAddon, error) {
+	desired := make([]*addons.DesiredAddon, 0, set.Len())
+
+	for _, raw := range set.List() {
+		tfMap, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		name, ok := tfMap["addon_name"].(string)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("addon_name is required for each addon block")
+		}
+
+		addon := &eks.Addon{
+			AddonName: aws.String(name),
+		}
+
+		if v, ok := tfMap["addon_version"].(string); ok && v != "" {
+			addon.AddonVersion = aws.String(v)
+		}
+
+		if v, ok := tfMap["service_account_role_arn"].(string); ok && v != "" {
+			addon.ServiceAccountRoleArn = aws.String(v)
+		}
+
+		desiredAddon := &addons.DesiredAddon{Addon: addon}
+		if v, ok := tfMap["resolve_conflicts_on_create"].(string); ok {
+			desiredAddon.ResolveConflictsOnCreate = v
+		}
+
+		desired = append(desired, desiredAddon)
+	}
+
+	return desired, nil
+}
+
+// diagnosticsFromAddonHealthErrors unwraps any *ekserr.AddonHealthError found in
+// err (which may be a *multierror.Error aggregating one per add-on) and
+// renders each reported issue as its own diagnostic. Every diagnostic is
+// attributed to the "addon" attribute as a whole; the failing add-on is named
+// in the summary, not in the attribute path.
+func diagnosticsFromAddonHealthErrors(err error) diag.Diagnostics {
+	var healthErrs []*ekserr.AddonHealthError
+
+	var merr *multierror.Error
+	if errors.As(err, &merr) {
+		for _, wrapped := range merr.Errors {
+			var healthErr *ekserr.AddonHealthError
+			if errors.As(wrapped, &healthErr) {
+				healthErrs = append(healthErrs, healthErr)
+			}
+		}
+	} else {
+		var healthErr *ekserr.AddonHealthError
+		if errors.As(err, &healthErr) {
+			healthErrs = append(healthErrs, healthErr)
+		}
+	}
+
+	var diags diag.Diagnostics
+	for _, healthErr := range healthErrs {
+		for _, issue := range healthErr.Issues() {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("EKS add-on %q: %s", healthErr.AddonName, issue.Code),
+				Detail:        fmt.Sprintf("%s (resources: %v)", issue.Message, issue.ResourceIds),
+				AttributePath: cty.GetAttrPath("addon"),
+			})
+		}
+	}
+
+	return diags
+}